@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsFollow bool
+	logsSince  string
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Tail the configured Sunshine log, printing only monitor-missing entries",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLogs(configPath, logsFollow, logsSince)
+	},
+}
+
+func init() {
+	logsCmd.Flags().BoolVar(&logsFollow, "follow", false, "keep watching the log for new monitor-missing entries")
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "only print entries at or after this RFC3339 timestamp")
+}
+
+// runLogs prints every monitor-missing entry already in the configured
+// Sunshine log, then, if follow is set, keeps printing new ones as they
+// appear, similar in spirit to `kpod logs --follow`.
+func runLogs(configPath string, follow bool, since string) error {
+	loadedConfig, err := readConfigFile(configPath)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	var sinceTime time.Time
+	if since != "" {
+		sinceTime, err = time.Parse(time.RFC3339, since)
+		if err != nil {
+			return fmt.Errorf("parsing --since: %w", err)
+		}
+	}
+
+	logFile, err := os.Open(loadedConfig.SunshineLogPath)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	printMonitorMissingLines(logFile, loadedConfig.MonitorIsOffLogLine, sinceTime)
+
+	if !follow {
+		return nil
+	}
+
+	return followMonitorMissingLines(logFile, loadedConfig.SunshineLogPath, loadedConfig.MonitorIsOffLogLine, sinceTime)
+}
+
+// printMonitorMissingLines scans r from its current position to EOF, printing
+// every line containing monitorIsOffLogLine whose timestamp is at or after
+// since.
+func printMonitorMissingLines(r io.Reader, monitorIsOffLogLine string, since time.Time) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.Contains(line, monitorIsOffLogLine) {
+			continue
+		}
+
+		entryTime, err := parseSunshineTimestamp(line)
+		if err != nil {
+			continue
+		}
+		if entryTime.Before(since) {
+			continue
+		}
+
+		fmt.Println(line)
+	}
+}
+
+// followMonitorMissingLines watches logPath for appended bytes and prints any
+// new monitor-missing entries among them, until the process is interrupted.
+// On rotation (the log's parent directory reports a rename or create of it,
+// two of the events runWatchLoop treats as rotation) it reopens logPath so it keeps reading
+// the new file instead of the stale, now-unlinked one.
+func followMonitorMissingLines(logFile *os.File, logPath, monitorIsOffLogLine string, since time.Time) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating fsnotify watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(logPath)); err != nil {
+		return fmt.Errorf("watching %s: %w", filepath.Dir(logPath), err)
+	}
+
+	current := logFile
+	defer current.Close()
+
+	for event := range watcher.Events {
+		if filepath.Clean(event.Name) != filepath.Clean(logPath) {
+			continue
+		}
+
+		if event.Op&(fsnotify.Rename|fsnotify.Create) != 0 {
+			current.Close()
+			reopened, err := os.Open(logPath)
+			if err != nil {
+				log.Println("Unable to reopen rotated Sunshine log:", err)
+				continue
+			}
+			current = reopened
+			printMonitorMissingLines(current, monitorIsOffLogLine, since)
+			continue
+		}
+
+		if event.Op&fsnotify.Write == fsnotify.Write {
+			printMonitorMissingLines(current, monitorIsOffLogLine, since)
+		}
+	}
+
+	return nil
+}
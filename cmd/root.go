@@ -0,0 +1,34 @@
+// Package cmd implements sunrise's cobra command tree: `run` (the daemon),
+// `logs`, `status`, and `test-wake`/`test-restart`.
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	configPath  string
+	pidfilePath string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "sunrise",
+	Short: "sunrise wakes a sleeping monitor when Sunshine logs a failed connection",
+	// Preserve backwards compatibility: invoking sunrise with no subcommand
+	// behaves like `sunrise run`, since that's how every existing install
+	// runs it today.
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRun(cmd.Context(), configPath, pidfilePath)
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "/etc/sunrise/sunrise.cfg", "path to the sunrise config file")
+	rootCmd.PersistentFlags().StringVar(&pidfilePath, "pidfile", "", "optional path to write sunrise's pid to, so operators can e.g. `kill -HUP $(cat pidfile)`")
+	rootCmd.AddCommand(runCmd, logsCmd, statusCmd, testWakeCmd, testRestartCmd)
+}
+
+// Execute runs the root command and returns any error it produced.
+func Execute() error {
+	return rootCmd.Execute()
+}
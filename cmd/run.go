@@ -0,0 +1,722 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fsnotify/fsnotify"
+	"github.com/samurailink3/sunrise/notifier"
+	"github.com/samurailink3/sunrise/supervisor"
+	"github.com/spf13/cobra"
+)
+
+// shutdownTimeout bounds how long graceful shutdown waits for the supervised
+// Sunshine process to stop before sunrise exits anyway.
+const shutdownTimeout = 10 * time.Second
+
+// defaultStateFilePath is used when config.StateFilePath is unset.
+const defaultStateFilePath = "/var/lib/sunrise/state.json"
+
+// monitorStateSchemaVersion is bumped whenever the on-disk monitorState shape
+// changes, so old state files are recognized as stale and discarded instead
+// of being misinterpreted.
+const monitorStateSchemaVersion = 1
+
+// monitorState is the on-disk snapshot of the log-scanning state, persisted
+// so a sunrise restart doesn't re-react to a monitor-missing entry that was
+// already handled before the restart.
+type monitorState struct {
+	SchemaVersion          int       `json:"schema_version"`
+	LastLogSize            int64     `json:"last_log_size"`
+	LastMonitorMissingTime time.Time `json:"last_monitor_missing_time"`
+	LogInode               uint64    `json:"log_inode"`
+}
+
+var (
+	// cfg holds the current config. It is swapped atomically on SIGHUP so the
+	// log-watching goroutine can keep reading it without locking.
+	cfg atomic.Value
+
+	sv *supervisor.Supervisor
+	nm *notifier.Manager
+
+	// Track the log file size and last handled error time so we only react to
+	// new Sunshine errors.
+	lastLogSize            int64
+	lastMonitorMissingTime time.Time
+)
+
+// config controls how sunrise functions. See `sunrise.cfg.example` for comments
+// on each item.
+type config struct {
+	SunriseCheckSeconds     int
+	SunshineLogPath         string
+	MonitorIsOffLogLine     string
+	WakeMonitorSleepSeconds int
+	StopSunshineCommand     string
+	StartSunshineCommand    string
+	WakeMonitorCommand      string
+	EnableSunshineRestart   bool
+
+	// UsePolling forces the legacy ticker-based full-log-scan behavior instead
+	// of inotify-based tailing. Useful as a compatibility fallback on
+	// filesystems where fsnotify is unsupported (e.g. some network mounts).
+	UsePolling bool
+
+	// The following control the Sunshine supervisor, used only when
+	// EnableSunshineRestart is true.
+	StartSeconds       int
+	StartRetries       int
+	BackoffBaseSeconds int
+	BackoffMaxSeconds  int
+
+	// StatusListenAddr is the address the supervisor's HTTP status endpoint
+	// listens on, e.g. "127.0.0.1:9090". Left empty, the status endpoint is
+	// disabled, and `sunrise status` has nothing to query.
+	StatusListenAddr string
+
+	// StateFilePath is where monitor-tracking state is persisted between
+	// restarts. Defaults to defaultStateFilePath when unset.
+	StateFilePath string
+
+	// Notifiers configures zero or more destinations to notify about
+	// monitor-missing, wake, restart, and error events; one per
+	// `[[notifier]]` TOML table.
+	Notifiers []notifier.Config `toml:"notifier"`
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the sunrise monitoring daemon",
+	Long:  "run watches the configured Sunshine log and wakes/restarts things on monitor-missing entries. This is sunrise's original, always-on behavior, and is what runs when no subcommand is given.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRun(cmd.Context(), configPath, pidfilePath)
+	},
+}
+
+// runRun implements the sunrise daemon: load config, restore any persisted
+// monitor state, start the Sunshine supervisor (if enabled), and watch the
+// Sunshine log until parentCtx is canceled.
+func runRun(parentCtx context.Context, configPath, pidfilePath string) error {
+	initialConfig, err := readConfigFile(configPath)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	cfg.Store(initialConfig)
+
+	if pidfilePath != "" {
+		if err := writePidfile(pidfilePath); err != nil {
+			return fmt.Errorf("writing pidfile: %w", err)
+		}
+		defer os.Remove(pidfilePath)
+	}
+
+	log.Println("Starting sunrise monitoring service")
+
+	ctx, cancel := context.WithCancel(parentCtx)
+	handleSignals(ctx, cancel, configPath)
+
+	loadMonitorState()
+
+	active := loadConfig()
+
+	nm, err = notifier.NewManager(ctx, active.Notifiers)
+	if err != nil {
+		return fmt.Errorf("configuring notifiers: %w", err)
+	}
+
+	if active.EnableSunshineRestart {
+		sv = supervisor.New(supervisor.Config{
+			StartCommand:       active.StartSunshineCommand,
+			StopCommand:        active.StopSunshineCommand,
+			StartSeconds:       active.StartSeconds,
+			StartRetries:       active.StartRetries,
+			BackoffBaseSeconds: active.BackoffBaseSeconds,
+			BackoffMaxSeconds:  active.BackoffMaxSeconds,
+		})
+		go sv.Run(ctx)
+
+		if active.StatusListenAddr != "" {
+			go serveStatus(active.StatusListenAddr)
+		}
+	}
+
+	var runErr error
+	if active.UsePolling {
+		log.Println("UsePolling is set; watching the Sunshine log with periodic full scans")
+		runErr = runPollingLoop(ctx)
+	} else {
+		runErr = runWatchLoop(ctx)
+		if runErr != nil && ctx.Err() == nil {
+			log.Println("Falling back to polling mode because the log watcher failed:", runErr)
+			runErr = runPollingLoop(ctx)
+		}
+	}
+
+	if runErr != nil && ctx.Err() == nil {
+		return fmt.Errorf("sunrise stopped unexpectedly: %w", runErr)
+	}
+
+	if sv != nil {
+		sv.WaitUntilStopped(shutdownTimeout)
+	}
+	log.Println("sunrise shut down")
+	return nil
+}
+
+// loadConfig returns the currently active config. Safe to call concurrently
+// with a SIGHUP reload.
+func loadConfig() config {
+	return cfg.Load().(config)
+}
+
+// readConfigFile decodes the TOML config at path into a fresh config value.
+func readConfigFile(path string) (config, error) {
+	var decoded config
+	if _, err := toml.DecodeFile(path, &decoded); err != nil {
+		return config{}, err
+	}
+	return decoded, nil
+}
+
+// writePidfile writes the current process's pid to path.
+func writePidfile(path string) error {
+	return os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())), 0644)
+}
+
+// handleSignals installs handlers for SIGINT/SIGTERM (graceful shutdown) and
+// SIGHUP (config reload). Shutdown cancels ctx, which unwinds the log-watching
+// loop and tells the supervisor (if any) to stop Sunshine.
+func handleSignals(ctx context.Context, cancel context.CancelFunc, configPath string) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-sigCh:
+				switch sig {
+				case syscall.SIGHUP:
+					reloadConfig(configPath)
+				case syscall.SIGINT, syscall.SIGTERM:
+					log.Println("Received", sig, "; shutting down")
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+}
+
+// startupOnlyConfigFields lists config fields that are only read once, at
+// startup, so a SIGHUP reload updates them in memory but can't make them take
+// effect: EnableSunshineRestart and StatusListenAddr are read once to decide
+// whether to build the supervisor and status server, UsePolling is read once
+// to pick runWatchLoop vs runPollingLoop, SunshineLogPath/SunriseCheckSeconds
+// are each baked into runWatchLoop's fsnotify watch and ticker at loop start
+// even though later code re-reads them live, Notifiers is read once to build
+// nm, and StartSunshineCommand/StopSunshineCommand/StartSeconds/StartRetries/
+// BackoffBaseSeconds/BackoffMaxSeconds are read once to build sv — neither nm
+// nor sv is ever rebuilt on reload.
+var startupOnlyConfigFields = map[string]bool{
+	"EnableSunshineRestart": true,
+	"UsePolling":            true,
+	"StatusListenAddr":      true,
+	"SunshineLogPath":       true,
+	"SunriseCheckSeconds":   true,
+	"Notifiers":             true,
+	"StartSunshineCommand":  true,
+	"StopSunshineCommand":   true,
+	"StartSeconds":          true,
+	"StartRetries":          true,
+	"BackoffBaseSeconds":    true,
+	"BackoffMaxSeconds":     true,
+}
+
+// reloadConfig re-reads the TOML config from configPath and atomically swaps
+// it in, logging which keys changed. Settings in startupOnlyConfigFields
+// still require a full restart to take effect.
+func reloadConfig(configPath string) {
+	newConfig, err := readConfigFile(configPath)
+	if err != nil {
+		log.Println("Error reloading config; keeping current config:", err)
+		return
+	}
+
+	logConfigDiff(loadConfig(), newConfig)
+	cfg.Store(newConfig)
+	log.Println("Config reloaded from", configPath)
+}
+
+// logConfigDiff logs each top-level config field that changed between oldCfg
+// and newCfg, warning when the changed field is startup-only and therefore
+// won't take effect until sunrise is restarted.
+func logConfigDiff(oldCfg, newCfg config) {
+	oldVal := reflect.ValueOf(oldCfg)
+	newVal := reflect.ValueOf(newCfg)
+	t := oldVal.Type()
+
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		oldField := oldVal.Field(i).Interface()
+		newField := newVal.Field(i).Interface()
+		if !reflect.DeepEqual(oldField, newField) {
+			name := t.Field(i).Name
+			log.Printf("Config change: %s: %v -> %v", name, oldField, newField)
+			if startupOnlyConfigFields[name] {
+				log.Printf("%s only takes effect at startup; restart sunrise to apply this change", name)
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		log.Println("Config reload: no fields changed")
+	}
+}
+
+// runPollingLoop re-scans the entire Sunshine log on a fixed interval. This is
+// the original sunrise behavior, kept as a compatibility mode for when
+// fsnotify is unavailable or misbehaves.
+func runPollingLoop(ctx context.Context) error {
+	ticker := time.NewTicker(time.Duration(loadConfig().SunriseCheckSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			monitorIsOff, err := isMonitorMissing()
+			if err != nil {
+				return err
+			}
+			if monitorIsOff {
+				reactToMonitorMissing(ctx)
+			}
+		}
+	}
+}
+
+// runWatchLoop watches the Sunshine log's parent directory with fsnotify and
+// only scans bytes appended to the log since the last read. Watching the
+// directory rather than the file itself means log rotation (which typically
+// replaces the file via rename or recreate) is noticed even though the
+// original file handle goes stale. A periodic
+// SunriseCheckSeconds tick is kept as a sanity check in case an event is
+// dropped by the kernel.
+func runWatchLoop(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating fsnotify watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	logDir := filepath.Dir(loadConfig().SunshineLogPath)
+	if err := watcher.Add(logDir); err != nil {
+		return fmt.Errorf("watching %s: %w", logDir, err)
+	}
+
+	// Establish a baseline, reacting immediately if a monitor-missing entry
+	// is already present (e.g. one that arrived while sunrise was down and
+	// was never handled).
+	if monitorIsOff, err := isMonitorMissing(); err != nil {
+		return err
+	} else if monitorIsOff {
+		reactToMonitorMissing(ctx)
+	}
+
+	ticker := time.NewTicker(time.Duration(loadConfig().SunriseCheckSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return fmt.Errorf("fsnotify watcher closed unexpectedly")
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(loadConfig().SunshineLogPath) {
+				continue
+			}
+
+			if event.Op&fsnotify.Write == fsnotify.Write {
+				monitorIsOff, err := scanNewLogBytes()
+				if err != nil {
+					log.Println("Unable to read log file:", err)
+					notify(notifier.EventError, fmt.Sprintf("unable to read Sunshine log: %v", err))
+					continue
+				}
+				if monitorIsOff {
+					reactToMonitorMissing(ctx)
+				}
+				continue
+			}
+
+			if event.Op&(fsnotify.Rename|fsnotify.Create|fsnotify.Remove) != 0 {
+				log.Println("Sunshine log appears to have rotated; resetting monitor-missing tracking state")
+				resetMonitorTracking()
+				lastLogSize = 0
+				saveMonitorState()
+			}
+		case watchErr, ok := <-watcher.Errors:
+			if !ok {
+				return fmt.Errorf("fsnotify watcher closed unexpectedly")
+			}
+			return watchErr
+		case <-ticker.C:
+			monitorIsOff, err := scanNewLogBytes()
+			if err != nil {
+				log.Println("Unable to read log file:", err)
+				notify(notifier.EventError, fmt.Sprintf("unable to read Sunshine log: %v", err))
+				continue
+			}
+			if monitorIsOff {
+				reactToMonitorMissing(ctx)
+			}
+		}
+	}
+}
+
+// reactToMonitorMissing runs the wake and (optionally) restart commands in
+// response to a detected monitor-missing entry. ctx is threaded through so
+// a shutdown in progress cancels an in-flight wake command.
+func reactToMonitorMissing(ctx context.Context) {
+	notify(notifier.EventWakeStarted, "Waking monitor")
+	if err := wakeMonitor(ctx); err != nil {
+		log.Println("Could not wake monitor:", err)
+		notify(notifier.EventError, fmt.Sprintf("wake monitor command failed: %v", err))
+	} else {
+		notify(notifier.EventWakeFinished, "Monitor wake command completed")
+	}
+
+	waitForMonitor()
+
+	// sv is only non-nil when EnableSunshineRestart was true at startup; that
+	// field is one of the startup-only settings (see reloadConfig) a SIGHUP
+	// can't turn on, so check sv rather than the live, reloadable config.
+	if sv != nil {
+		notify(notifier.EventRestartStarted, "Restarting sunshine")
+		if sv.Restart() {
+			notify(notifier.EventRestartFinished, "Sunshine restart requested")
+		} else {
+			log.Println("Could not restart Sunshine: supervisor has given up after exhausting its retry budget")
+			notify(notifier.EventError, "Sunshine restart failed: supervisor has given up after exhausting its retry budget")
+		}
+	}
+
+	saveMonitorState()
+}
+
+// notify sends event to the configured notifiers, if any are configured.
+func notify(eventType notifier.EventType, message string) {
+	if nm == nil {
+		return
+	}
+	nm.Notify(notifier.Event{Type: eventType, Time: time.Now(), Message: message})
+}
+
+// serveStatus runs the supervisor's HTTP status endpoint until it fails.
+func serveStatus(addr string) {
+	log.Println("Serving supervisor status on", addr)
+	if err := http.ListenAndServe(addr, sv.StatusHandler()); err != nil {
+		log.Println("Supervisor status server stopped:", err)
+	}
+}
+
+// isMonitorMissing will search the current Sunshine log file for evidence that
+// a client tried to connect to Sunshine and found the monitor was off. It
+// returns `true` if the monitor is off.
+func isMonitorMissing() (monitorIsMissing bool, err error) {
+	log.Println("Checking if monitor is missing according to Sunshine log")
+	logPath := loadConfig().SunshineLogPath
+	logInfo, err := os.Stat(logPath)
+	if err != nil {
+		return false, err
+	}
+
+	if logInfo.Size() < lastLogSize {
+		// Sunshine rewrote the log, so the next matching line should trigger again.
+		log.Println("Sunshine log appears to have rotated; resetting monitor-missing tracking state")
+		resetMonitorTracking()
+	}
+
+	lastLogSize = logInfo.Size()
+
+	logFile, err := os.Open(logPath)
+	if err != nil {
+		return false, err
+	}
+	defer logFile.Close()
+
+	latestOccurrence, err := findLatestMonitorMissingOccurrence(logFile)
+	if err != nil {
+		return false, err
+	}
+
+	monitorIsMissing = evaluateMonitorMissing(latestOccurrence)
+	saveMonitorState()
+	return monitorIsMissing, nil
+}
+
+// scanNewLogBytes reads only the bytes appended to the Sunshine log since
+// lastLogSize and looks for a monitor-missing entry among them. It is the
+// incremental counterpart to isMonitorMissing, used when reacting to fsnotify
+// Write events so the whole log isn't re-scanned on every tick.
+func scanNewLogBytes() (monitorIsMissing bool, err error) {
+	logPath := loadConfig().SunshineLogPath
+	logInfo, err := os.Stat(logPath)
+	if err != nil {
+		return false, err
+	}
+
+	if logInfo.Size() < lastLogSize {
+		log.Println("Sunshine log appears to have rotated; resetting monitor-missing tracking state")
+		resetMonitorTracking()
+		lastLogSize = 0
+	}
+
+	if logInfo.Size() == lastLogSize {
+		return false, nil
+	}
+
+	logFile, err := os.Open(logPath)
+	if err != nil {
+		return false, err
+	}
+	defer logFile.Close()
+
+	if _, err := logFile.Seek(lastLogSize, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	latestOccurrence, err := findLatestMonitorMissingOccurrence(logFile)
+	if err != nil {
+		return false, err
+	}
+
+	lastLogSize = logInfo.Size()
+
+	monitorIsMissing = evaluateMonitorMissing(latestOccurrence)
+	saveMonitorState()
+	return monitorIsMissing, nil
+}
+
+// findLatestMonitorMissingOccurrence walks r looking for the newest
+// monitor-missing log entry and returns its timestamp, or the zero Time if
+// none was found.
+func findLatestMonitorMissingOccurrence(r io.Reader) (time.Time, error) {
+	monitorIsOffLogLine := loadConfig().MonitorIsOffLogLine
+	var latestOccurrence time.Time
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.Contains(line, monitorIsOffLogLine) {
+			continue
+		}
+
+		entryTime, err := parseSunshineTimestamp(line)
+		if err != nil {
+			log.Printf("Unable to parse Sunshine log timestamp for line %q: %v", line, err)
+			continue
+		}
+
+		if entryTime.After(latestOccurrence) {
+			latestOccurrence = entryTime
+		}
+	}
+
+	return latestOccurrence, scanner.Err()
+}
+
+// evaluateMonitorMissing compares a newly observed monitor-missing timestamp
+// against the last one we reacted to, updating the tracked state and
+// returning whether this occurrence warrants a reaction.
+func evaluateMonitorMissing(latestOccurrence time.Time) bool {
+	if latestOccurrence.IsZero() {
+		return false
+	}
+
+	if lastMonitorMissingTime.IsZero() || latestOccurrence.After(lastMonitorMissingTime) {
+		lastMonitorMissingTime = latestOccurrence
+		log.Println("Monitor is missing; last Sunshine error at", latestOccurrence.Format(time.RFC3339Nano))
+		notify(notifier.EventMonitorMissing, fmt.Sprintf("Monitor missing; last Sunshine error at %s", latestOccurrence.Format(time.RFC3339Nano)))
+		return true
+	}
+
+	log.Println("Monitor missing error already handled at", lastMonitorMissingTime.Format(time.RFC3339Nano))
+	return false
+}
+
+// wakeMonitor runs the configured command to wake the connected monitor from
+// sleep. The command is tied to ctx so a shutdown in progress interrupts it.
+func wakeMonitor(ctx context.Context) (err error) {
+	wakeMonitorCommandAndArgs := strings.Split(loadConfig().WakeMonitorCommand, " ")
+	wakeCMD := exec.CommandContext(ctx, wakeMonitorCommandAndArgs[0], wakeMonitorCommandAndArgs[1:]...)
+	log.Println("Running wakeMonitor command:", wakeCMD.String())
+	err = wakeCMD.Run()
+	if err != nil {
+		return err
+	}
+	log.Println("wakeMonitor command completed without errors")
+	return nil
+}
+
+// resetMonitorTracking resets the in-memory timestamp to track the last
+// timestamp the monitor when missing.
+func resetMonitorTracking() {
+	lastMonitorMissingTime = time.Time{}
+}
+
+// parseSunshineTimestamp will obtain a Go-native timestamp out of the Sunshine
+// logs.
+func parseSunshineTimestamp(line string) (time.Time, error) {
+	// Sunshine timestamps appear as: [YYYY-MM-DD HH:MM:SS.mmm]
+	endIdx := strings.Index(line, "]")
+	if !strings.HasPrefix(line, "[") || endIdx == -1 {
+		return time.Time{}, fmt.Errorf("sunshine log line missing timestamp brackets")
+	}
+
+	timePortion := line[1:endIdx]
+	t, err := time.ParseInLocation("2006-01-02 15:04:05.000", timePortion, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t, nil
+}
+
+// waitForMonitor will sleep for a configured amount of seconds for the monitor
+// to wake up.
+func waitForMonitor() {
+	sleepSeconds := loadConfig().WakeMonitorSleepSeconds
+	log.Println("Waiting", sleepSeconds, "seconds for monitor to come up")
+	time.Sleep(time.Duration(sleepSeconds) * time.Second)
+}
+
+// stateFilePath returns the configured monitor state path, or
+// defaultStateFilePath if none is set.
+func stateFilePath() string {
+	if path := loadConfig().StateFilePath; path != "" {
+		return path
+	}
+	return defaultStateFilePath
+}
+
+// loadMonitorState restores lastLogSize and lastMonitorMissingTime from the
+// state file, if one exists and still matches the current Sunshine log. A
+// missing, corrupt, or outdated-schema file is treated as "nothing to
+// restore" rather than an error, since sunrise has always coped with
+// starting from a blank slate.
+func loadMonitorState() {
+	path := stateFilePath()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Println("Unable to read monitor state file; starting fresh:", err)
+		}
+		return
+	}
+
+	var st monitorState
+	if err := json.Unmarshal(data, &st); err != nil {
+		log.Println("Unable to parse monitor state file; starting fresh:", err)
+		return
+	}
+
+	if st.SchemaVersion != monitorStateSchemaVersion {
+		log.Println("Monitor state file has an unrecognized schema version; starting fresh")
+		return
+	}
+
+	currentInode, err := logInode(loadConfig().SunshineLogPath)
+	if err != nil {
+		log.Println("Unable to stat Sunshine log; starting fresh:", err)
+		return
+	}
+
+	if currentInode != st.LogInode {
+		log.Println("Sunshine log inode does not match saved state; treating as rotated and starting fresh")
+		return
+	}
+
+	lastLogSize = st.LastLogSize
+	lastMonitorMissingTime = st.LastMonitorMissingTime
+	log.Println("Restored monitor state from", path)
+}
+
+// saveMonitorState atomically writes the current monitor-tracking state to
+// disk via a temp file plus rename, so a crash mid-write can't corrupt the
+// state file sunrise reads on its next start.
+func saveMonitorState() {
+	inode, err := logInode(loadConfig().SunshineLogPath)
+	if err != nil {
+		log.Println("Unable to stat Sunshine log while saving monitor state:", err)
+		return
+	}
+
+	st := monitorState{
+		SchemaVersion:          monitorStateSchemaVersion,
+		LastLogSize:            lastLogSize,
+		LastMonitorMissingTime: lastMonitorMissingTime,
+		LogInode:               inode,
+	}
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		log.Println("Unable to marshal monitor state:", err)
+		return
+	}
+
+	path := stateFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Println("Unable to create monitor state directory:", err)
+		return
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		log.Println("Unable to write monitor state file:", err)
+		return
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		log.Println("Unable to move monitor state file into place:", err)
+	}
+}
+
+// logInode returns the inode number of the file at path, used to detect log
+// rotation across a sunrise restart.
+func logInode(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("unable to determine inode for %s on this platform", path)
+	}
+
+	return stat.Ino, nil
+}
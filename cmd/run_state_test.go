@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// withTestConfig points the package-level cfg and monitor-tracking vars at a
+// scratch log file and state file for the duration of a test, restoring them
+// afterward since loadMonitorState/saveMonitorState read and write globals.
+func withTestConfig(t *testing.T) (logPath, statePath string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	logPath = filepath.Join(dir, "sunshine.log")
+	statePath = filepath.Join(dir, "state.json")
+
+	if err := os.WriteFile(logPath, []byte("log contents"), 0644); err != nil {
+		t.Fatalf("writing scratch log file: %v", err)
+	}
+
+	prevCfg := cfg.Load()
+	prevLogSize := lastLogSize
+	prevMissingTime := lastMonitorMissingTime
+	t.Cleanup(func() {
+		if prevCfg != nil {
+			cfg.Store(prevCfg)
+		}
+		lastLogSize = prevLogSize
+		lastMonitorMissingTime = prevMissingTime
+	})
+
+	cfg.Store(config{SunshineLogPath: logPath, StateFilePath: statePath})
+	return logPath, statePath
+}
+
+func TestMonitorStateRoundTrip(t *testing.T) {
+	_, statePath := withTestConfig(t)
+
+	lastLogSize = 42
+	lastMonitorMissingTime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	saveMonitorState()
+
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("expected state file at %s: %v", statePath, err)
+	}
+
+	lastLogSize = 0
+	lastMonitorMissingTime = time.Time{}
+	loadMonitorState()
+
+	if lastLogSize != 42 {
+		t.Errorf("lastLogSize = %d, want 42", lastLogSize)
+	}
+	if !lastMonitorMissingTime.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("lastMonitorMissingTime = %v, want 2026-01-02T03:04:05Z", lastMonitorMissingTime)
+	}
+}
+
+func TestLoadMonitorStateDiscardsStateAfterLogRotation(t *testing.T) {
+	logPath, _ := withTestConfig(t)
+
+	lastLogSize = 42
+	lastMonitorMissingTime = time.Now()
+	saveMonitorState()
+
+	// Simulate rotation the way Sunshine does it: write the new log under a
+	// different name, then rename it into place, guaranteeing a new inode
+	// rather than risking the filesystem reusing the old one.
+	replacement := logPath + ".new"
+	if err := os.WriteFile(replacement, []byte("new log contents"), 0644); err != nil {
+		t.Fatalf("writing replacement log file: %v", err)
+	}
+	if err := os.Rename(replacement, logPath); err != nil {
+		t.Fatalf("rotating log file: %v", err)
+	}
+
+	lastLogSize = 0
+	lastMonitorMissingTime = time.Time{}
+	loadMonitorState()
+
+	if lastLogSize != 0 {
+		t.Errorf("lastLogSize = %d, want 0 after a detected rotation", lastLogSize)
+	}
+	if !lastMonitorMissingTime.IsZero() {
+		t.Errorf("lastMonitorMissingTime = %v, want zero value after a detected rotation", lastMonitorMissingTime)
+	}
+}
+
+func TestLoadMonitorStateMissingFileStartsFresh(t *testing.T) {
+	withTestConfig(t)
+
+	lastLogSize = 7
+	loadMonitorState()
+
+	if lastLogSize != 7 {
+		t.Errorf("lastLogSize = %d, want unchanged at 7 when no state file exists", lastLogSize)
+	}
+}
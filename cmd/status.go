@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/samurailink3/sunrise/supervisor"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Query the running sunrise daemon's supervisor status",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStatus(configPath)
+	},
+}
+
+// runStatus queries the running daemon's supervisor status endpoint and
+// prints the supervisor's Sunshine state, retry count, Sunshine PID, and the
+// time and error of Sunshine's last exit. It does not report the
+// monitor-missing tracking state or the time of the last monitor-missing
+// entry in the Sunshine log.
+func runStatus(configPath string) error {
+	loadedConfig, err := readConfigFile(configPath)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	if loadedConfig.StatusListenAddr == "" {
+		return fmt.Errorf("StatusListenAddr is not set in config; there is no status endpoint to query")
+	}
+
+	resp, err := http.Get("http://" + loadedConfig.StatusListenAddr)
+	if err != nil {
+		return fmt.Errorf("querying status endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var status supervisor.Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return fmt.Errorf("decoding status response: %w", err)
+	}
+
+	fmt.Printf("State:   %s\n", status.State)
+	fmt.Printf("Retries: %d\n", status.Retries)
+	if status.PID != 0 {
+		fmt.Printf("PID:     %d\n", status.PID)
+	}
+	if !status.LastExitAt.IsZero() {
+		fmt.Printf("Last exit: %s (%s)\n", status.LastExitAt.Format(time.RFC3339), status.LastExitErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var testWakeCmd = &cobra.Command{
+	Use:   "test-wake",
+	Short: "Run the configured wake-monitor command once and exit non-zero on failure",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loadedConfig, err := readConfigFile(configPath)
+		if err != nil {
+			return fmt.Errorf("reading config file: %w", err)
+		}
+		return runConfiguredCommand(cmd.Context(), "WakeMonitorCommand", loadedConfig.WakeMonitorCommand)
+	},
+}
+
+var testRestartCmd = &cobra.Command{
+	Use:   "test-restart",
+	Short: "Stop and start Sunshine once using the configured commands, and exit non-zero on failure",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTestRestart(cmd.Context(), configPath)
+	},
+}
+
+// runTestRestart stops then starts Sunshine using the configured commands, so
+// operators can validate their config without waiting for a real disconnect.
+// A non-zero stop command is expected when Sunshine isn't already running, so
+// only the start command's failure is fatal.
+func runTestRestart(ctx context.Context, configPath string) error {
+	loadedConfig, err := readConfigFile(configPath)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	if err := runCommandLine(ctx, loadedConfig.StopSunshineCommand); err != nil {
+		fmt.Fprintln(os.Stderr, "stop command failed (continuing):", err)
+	}
+
+	return runConfiguredCommand(ctx, "StartSunshineCommand", loadedConfig.StartSunshineCommand)
+}
+
+// runConfiguredCommand runs commandLine, reporting which config key it came
+// from if it's unset or fails.
+func runConfiguredCommand(ctx context.Context, configKey, commandLine string) error {
+	if commandLine == "" {
+		return fmt.Errorf("%s is not set in config", configKey)
+	}
+	return runCommandLine(ctx, commandLine)
+}
+
+func runCommandLine(ctx context.Context, commandLine string) error {
+	parts := strings.Fields(commandLine)
+	if len(parts) == 0 {
+		return fmt.Errorf("empty command")
+	}
+
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,34 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// execNotifier runs a local command with the rendered message as its final
+// argument.
+type execNotifier struct {
+	cfg Config
+}
+
+func newExecNotifier(cfg Config) (Notifier, error) {
+	if cfg.Command == "" {
+		return nil, fmt.Errorf("notifier: exec requires Command")
+	}
+	return &execNotifier{cfg: cfg}, nil
+}
+
+func (n *execNotifier) Notify(ctx context.Context, event Event) error {
+	parts := strings.Fields(n.cfg.Command)
+	if len(parts) == 0 {
+		return fmt.Errorf("notifier: exec Command %q has no executable", n.cfg.Command)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	args := append(append([]string{}, parts[1:]...), renderMessage(n.cfg, event))
+	return exec.CommandContext(ctx, parts[0], args...).Run()
+}
@@ -0,0 +1,21 @@
+package notifier
+
+import (
+	"context"
+	"log"
+)
+
+// logNotifier writes the rendered message through the standard logger. It
+// needs no config and is a reasonable default destination.
+type logNotifier struct {
+	cfg Config
+}
+
+func newLogNotifier(cfg Config) Notifier {
+	return &logNotifier{cfg: cfg}
+}
+
+func (n *logNotifier) Notify(_ context.Context, event Event) error {
+	log.Println(renderMessage(n.cfg, event))
+	return nil
+}
@@ -0,0 +1,99 @@
+package notifier
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// queueDepth bounds how many pending events a single notifier's worker will
+// hold before dropping the oldest to make room for the newest.
+const queueDepth = 32
+
+// maxDeliverAttempts bounds how many times a single event is retried against
+// one notifier before it's given up on.
+const maxDeliverAttempts = 3
+
+// Manager fans an Event out to all configured notifiers, each through its own
+// bounded, drop-oldest queue and delivery worker, so a slow or failing
+// notifier can't stall the caller or the others.
+type Manager struct {
+	workers []*worker
+}
+
+type worker struct {
+	notifier Notifier
+	queue    chan Event
+}
+
+// NewManager builds a Notifier for each cfg and starts its delivery worker.
+// The workers run until ctx is canceled.
+func NewManager(ctx context.Context, cfgs []Config) (*Manager, error) {
+	m := &Manager{}
+	for _, c := range cfgs {
+		n, err := New(c)
+		if err != nil {
+			return nil, err
+		}
+
+		w := &worker{notifier: n, queue: make(chan Event, queueDepth)}
+		go w.run(ctx)
+		m.workers = append(m.workers, w)
+	}
+	return m, nil
+}
+
+// Notify enqueues event for delivery to every configured notifier. It never
+// blocks: if a notifier's queue is full, the oldest queued event is dropped
+// to make room for this one.
+func (m *Manager) Notify(event Event) {
+	for _, w := range m.workers {
+		select {
+		case w.queue <- event:
+		default:
+			select {
+			case <-w.queue:
+			default:
+			}
+			select {
+			case w.queue <- event:
+			default:
+			}
+		}
+	}
+}
+
+func (w *worker) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-w.queue:
+			w.deliverWithRetry(ctx, event)
+		}
+	}
+}
+
+// deliverWithRetry attempts delivery up to maxDeliverAttempts times, with
+// exponential backoff between attempts.
+func (w *worker) deliverWithRetry(ctx context.Context, event Event) {
+	backoff := time.Second
+	for attempt := 1; attempt <= maxDeliverAttempts; attempt++ {
+		err := w.notifier.Notify(ctx, event)
+		if err == nil {
+			return
+		}
+
+		log.Printf("notifier: delivery attempt %d/%d failed: %v", attempt, maxDeliverAttempts, err)
+		if attempt == maxDeliverAttempts {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
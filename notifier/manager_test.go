@@ -0,0 +1,46 @@
+package notifier
+
+import "testing"
+
+// TestManagerNotifyDropsOldestWhenFull exercises Notify's drop-oldest
+// behavior directly against a worker's queue, without starting its delivery
+// goroutine, so the queue only drains via the drop-oldest path under test.
+func TestManagerNotifyDropsOldestWhenFull(t *testing.T) {
+	w := &worker{queue: make(chan Event, 2)}
+	m := &Manager{workers: []*worker{w}}
+
+	m.Notify(Event{Message: "first"})
+	m.Notify(Event{Message: "second"})
+	m.Notify(Event{Message: "third"}) // queue is full; "first" should be dropped
+
+	if got := len(w.queue); got != 2 {
+		t.Fatalf("queue length = %d, want 2", got)
+	}
+
+	want := []string{"second", "third"}
+	for _, wantMsg := range want {
+		got := <-w.queue
+		if got.Message != wantMsg {
+			t.Errorf("queue order: got %q, want %q", got.Message, wantMsg)
+		}
+	}
+}
+
+func TestManagerNotifyFansOutToAllWorkers(t *testing.T) {
+	w1 := &worker{queue: make(chan Event, 1)}
+	w2 := &worker{queue: make(chan Event, 1)}
+	m := &Manager{workers: []*worker{w1, w2}}
+
+	m.Notify(Event{Message: "hello"})
+
+	for _, w := range []*worker{w1, w2} {
+		select {
+		case event := <-w.queue:
+			if event.Message != "hello" {
+				t.Errorf("queued event = %q, want %q", event.Message, "hello")
+			}
+		default:
+			t.Error("expected an event in worker queue, got none")
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// mqttNotifier publishes the rendered message to a topic on an MQTT broker.
+// A fresh connection is made per notification rather than held open, since
+// notifications are infrequent and this keeps reconnect handling simple.
+type mqttNotifier struct {
+	cfg Config
+}
+
+func newMQTTNotifier(cfg Config) (Notifier, error) {
+	if cfg.Broker == "" || cfg.Topic == "" {
+		return nil, fmt.Errorf("notifier: mqtt requires Broker and Topic")
+	}
+	return &mqttNotifier{cfg: cfg}, nil
+}
+
+func (n *mqttNotifier) Notify(ctx context.Context, event Event) error {
+	opts := mqtt.NewClientOptions().AddBroker(n.cfg.Broker).SetClientID("sunrise")
+	client := mqtt.NewClient(opts)
+	defer client.Disconnect(250)
+
+	if token := client.Connect(); !token.WaitTimeout(10*time.Second) || token.Error() != nil {
+		if token.Error() != nil {
+			return fmt.Errorf("connecting to mqtt broker: %w", token.Error())
+		}
+		return fmt.Errorf("connecting to mqtt broker: timed out")
+	}
+
+	token := client.Publish(n.cfg.Topic, 0, false, renderMessage(n.cfg, event))
+	if !token.WaitTimeout(10 * time.Second) {
+		return fmt.Errorf("publishing to mqtt topic %s: timed out", n.cfg.Topic)
+	}
+	return token.Error()
+}
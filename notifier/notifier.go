@@ -0,0 +1,90 @@
+// Package notifier delivers sunrise lifecycle events (monitor-missing
+// detected, wake/restart started and finished, and error conditions) to zero
+// or more configured destinations without blocking the caller.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// notifyTimeout bounds a single delivery attempt, so a destination that
+// accepts a connection but never responds can't stall a notifier's worker
+// (and thus its retry loop) for the life of the process.
+const notifyTimeout = 10 * time.Second
+
+// EventType identifies what happened.
+type EventType string
+
+const (
+	EventMonitorMissing  EventType = "monitor_missing"
+	EventWakeStarted     EventType = "wake_started"
+	EventWakeFinished    EventType = "wake_finished"
+	EventRestartStarted  EventType = "restart_started"
+	EventRestartFinished EventType = "restart_finished"
+	EventError           EventType = "error"
+)
+
+// Event carries a single sunrise lifecycle notification.
+type Event struct {
+	Type    EventType
+	Time    time.Time
+	Message string
+}
+
+// Notifier delivers an Event to some destination.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Config configures a single notifier instance, corresponding to one
+// `[[notifier]]` TOML table.
+type Config struct {
+	// Type selects the notifier implementation: "webhook", "exec", "mqtt", or
+	// "log".
+	Type string
+
+	// Template formats the message sent to the destination. It is passed
+	// through fmt.Sprintf-style formatting with the event's type and message
+	// as arguments; left empty, a sensible default is used.
+	Template string
+
+	// URL is the webhook endpoint, used when Type is "webhook".
+	URL string
+
+	// Command is run with the rendered message as its final argument, used
+	// when Type is "exec".
+	Command string
+
+	// Broker and Topic configure the MQTT connection, used when Type is
+	// "mqtt".
+	Broker string
+	Topic  string
+}
+
+// New builds the Notifier described by cfg.
+func New(cfg Config) (Notifier, error) {
+	switch cfg.Type {
+	case "webhook":
+		return newWebhookNotifier(cfg)
+	case "exec":
+		return newExecNotifier(cfg)
+	case "mqtt":
+		return newMQTTNotifier(cfg)
+	case "log":
+		return newLogNotifier(cfg), nil
+	default:
+		return nil, fmt.Errorf("notifier: unknown type %q", cfg.Type)
+	}
+}
+
+// renderMessage formats event according to cfg.Template, defaulting to a
+// plain "[type] message" rendering when no template is configured.
+func renderMessage(cfg Config, event Event) string {
+	template := cfg.Template
+	if template == "" {
+		template = "[%s] %s"
+	}
+	return fmt.Sprintf(template, event.Type, event.Message)
+}
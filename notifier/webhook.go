@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// webhookNotifier POSTs a JSON body to a configured URL.
+type webhookNotifier struct {
+	cfg Config
+}
+
+func newWebhookNotifier(cfg Config) (Notifier, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("notifier: webhook requires URL")
+	}
+	return &webhookNotifier{cfg: cfg}, nil
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, event Event) error {
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(struct {
+		Type    EventType `json:"type"`
+		Time    string    `json:"time"`
+		Message string    `json:"message"`
+	}{
+		Type:    event.Type,
+		Time:    event.Time.Format("2006-01-02T15:04:05.000Z07:00"),
+		Message: renderMessage(n.cfg, event),
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling webhook body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+// Package supervisor owns the Sunshine child process for its full lifetime,
+// restarting it with exponential backoff when it exits unexpectedly instead
+// of the fire-and-forget start/stop sunrise used previously.
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// State is one of the supervisor's lifecycle states.
+type State string
+
+const (
+	StateStopped  State = "stopped"
+	StateStarting State = "starting"
+	StateRunning  State = "running"
+	StateBackoff  State = "backoff"
+	StateFatal    State = "fatal"
+)
+
+// Config controls how the supervisor starts, stops, and retries Sunshine.
+type Config struct {
+	StartCommand string
+	StopCommand  string
+
+	// StartSeconds is how long a freshly started Sunshine must stay up before
+	// the supervisor considers the start successful and resets the retry
+	// counter, rather than counting a later crash against the same backoff
+	// streak.
+	StartSeconds int
+
+	// StartRetries is the number of consecutive failed starts allowed before
+	// the supervisor gives up and transitions to StateFatal.
+	StartRetries int
+
+	// BackoffBaseSeconds and BackoffMaxSeconds bound the exponential backoff
+	// applied between restart attempts: base * 2^(attempt-1), capped at max,
+	// with up to 50% jitter added.
+	BackoffBaseSeconds int
+	BackoffMaxSeconds  int
+}
+
+// Status is a point-in-time snapshot of the supervisor, safe to serialize.
+type Status struct {
+	State       State     `json:"state"`
+	Retries     int       `json:"retries"`
+	PID         int       `json:"pid,omitempty"`
+	LastExitAt  time.Time `json:"last_exit_at,omitempty"`
+	LastExitErr string    `json:"last_exit_err,omitempty"`
+}
+
+// Supervisor owns a Sunshine child process, restarting it with exponential
+// backoff if it exits unexpectedly.
+type Supervisor struct {
+	cfg Config
+
+	mu          sync.Mutex
+	state       State
+	retries     int
+	cmd         *exec.Cmd
+	lastExitAt  time.Time
+	lastExitErr error
+	stopping    bool
+
+	// restartCh wakes waitForRetry up early when Restart is called while the
+	// supervisor is in StateBackoff, so the operator-initiated restart
+	// doesn't have to wait out the remaining backoff delay.
+	restartCh chan struct{}
+}
+
+// New creates a Supervisor for the given config.
+func New(cfg Config) *Supervisor {
+	return &Supervisor{cfg: cfg, state: StateStopped, restartCh: make(chan struct{}, 1)}
+}
+
+// Run spawns Sunshine and keeps it running, restarting it with backoff on
+// unexpected exit, until ctx is canceled or the retry budget is exhausted.
+// Run blocks; callers typically invoke it in a goroutine.
+func (s *Supervisor) Run(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			s.setState(StateStopped)
+			return
+		}
+
+		s.setState(StateStarting)
+		startedAt := time.Now()
+		exited, err := s.spawn()
+		if err != nil {
+			s.recordExit(err)
+			if !s.waitForRetry(ctx) {
+				return
+			}
+			continue
+		}
+
+		// Drop any restart request left over from while Sunshine was starting
+		// up, so it can't be mistaken for a fresh request and shortcut a
+		// future backoff it was never meant for.
+		select {
+		case <-s.restartCh:
+		default:
+		}
+		s.setState(StateRunning)
+
+		select {
+		case <-ctx.Done():
+			runStopCommand(s.cfg.StopCommand)
+			<-exited
+			s.mu.Lock()
+			s.cmd = nil
+			s.mu.Unlock()
+			s.setState(StateStopped)
+			return
+		case exitErr := <-exited:
+			s.mu.Lock()
+			s.cmd = nil
+			stopping := s.stopping
+			s.stopping = false
+			s.mu.Unlock()
+
+			if stopping {
+				// Restart() triggered this exit; loop straight back into
+				// StateStarting without counting it against the backoff budget.
+				continue
+			}
+
+			s.recordExit(exitErr)
+			if time.Since(startedAt) >= time.Duration(s.cfg.StartSeconds)*time.Second {
+				s.resetRetries()
+			}
+			if !s.waitForRetry(ctx) {
+				return
+			}
+		}
+	}
+}
+
+// Restart asks Run to relaunch Sunshine immediately, bypassing the backoff
+// delay since this is an operator-initiated restart rather than a crash. It
+// reports whether a restart was actually possible: while StateRunning or
+// StateBackoff, Run is nudged toward a fresh spawn and Restart returns true;
+// while StateFatal, Run has already given up and returned, so there's no
+// running loop left to restart, and Restart returns false without doing
+// anything.
+func (s *Supervisor) Restart() bool {
+	switch s.Status().State {
+	case StateRunning:
+		s.mu.Lock()
+		s.stopping = true
+		s.mu.Unlock()
+		runStopCommand(s.cfg.StopCommand)
+		return true
+	case StateStarting, StateBackoff:
+		select {
+		case s.restartCh <- struct{}{}:
+		default:
+		}
+		return true
+	default: // StateStopped, StateFatal
+		return false
+	}
+}
+
+// WaitUntilStopped blocks until the supervisor reaches StateStopped or
+// timeout elapses, returning whether it stopped in time. Callers should
+// cancel the context passed to Run first so Run actually heads toward
+// StateStopped instead of restarting Sunshine.
+func (s *Supervisor) WaitUntilStopped(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for s.Status().State != StateStopped {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return true
+}
+
+// Status returns a snapshot of the supervisor's current state.
+func (s *Supervisor) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{
+		State:      s.state,
+		Retries:    s.retries,
+		LastExitAt: s.lastExitAt,
+	}
+	if s.lastExitErr != nil {
+		status.LastExitErr = s.lastExitErr.Error()
+	}
+	if s.cmd != nil && s.cmd.Process != nil {
+		status.PID = s.cmd.Process.Pid
+	}
+	return status
+}
+
+// StatusHandler returns an http.Handler that serves the current Status as JSON.
+func (s *Supervisor) StatusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(s.Status()); err != nil {
+			log.Println("supervisor: failed to write status response:", err)
+		}
+	})
+}
+
+func (s *Supervisor) spawn() (chan error, error) {
+	args := strings.Fields(s.cfg.StartCommand)
+	cmd := exec.Command(args[0], args[1:]...)
+	log.Println("supervisor: starting sunshine:", cmd.String())
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cmd = cmd
+	s.mu.Unlock()
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	return exited, nil
+}
+
+func (s *Supervisor) waitForRetry(ctx context.Context) bool {
+	s.mu.Lock()
+	s.retries++
+	retries := s.retries
+	s.mu.Unlock()
+
+	if retries > s.cfg.StartRetries {
+		s.setState(StateFatal)
+		log.Printf("supervisor: sunshine failed to stay up after %d attempts; giving up", s.cfg.StartRetries)
+		return false
+	}
+
+	delay := s.backoffDelay(retries)
+	s.setState(StateBackoff)
+	log.Printf("supervisor: sunshine exited, retrying in %s (attempt %d/%d)", delay, retries, s.cfg.StartRetries)
+
+	select {
+	case <-ctx.Done():
+		s.setState(StateStopped)
+		return false
+	case <-s.restartCh:
+		log.Println("supervisor: restart requested during backoff; retrying immediately")
+		return true
+	case <-time.After(delay):
+		return true
+	}
+}
+
+// backoffDelay computes base * 2^(attempt-1), capped at BackoffMaxSeconds,
+// with up to 50% jitter added to avoid synchronized retry storms.
+func (s *Supervisor) backoffDelay(attempt int) time.Duration {
+	base := float64(s.cfg.BackoffBaseSeconds)
+	max := float64(s.cfg.BackoffMaxSeconds)
+
+	backoff := base * math.Pow(2, float64(attempt-1))
+	if backoff > max {
+		backoff = max
+	}
+
+	jittered := backoff * (1 + rand.Float64()*0.5)
+	return time.Duration(jittered * float64(time.Second))
+}
+
+func (s *Supervisor) recordExit(err error) {
+	s.mu.Lock()
+	s.lastExitAt = time.Now()
+	s.lastExitErr = err
+	s.mu.Unlock()
+	log.Println("supervisor: sunshine exited:", err)
+}
+
+func (s *Supervisor) resetRetries() {
+	s.mu.Lock()
+	s.retries = 0
+	s.mu.Unlock()
+}
+
+func (s *Supervisor) setState(state State) {
+	s.mu.Lock()
+	s.state = state
+	s.mu.Unlock()
+}
+
+// runStopCommand runs the configured stop command. Errors are logged but
+// ignored, since the stop command (e.g. `killall`) commonly exits non-zero
+// when Sunshine isn't currently running.
+func runStopCommand(stopCommand string) {
+	args := strings.Fields(stopCommand)
+	cmd := exec.Command(args[0], args[1:]...)
+	log.Println("supervisor: running stop command:", cmd.String())
+	if err := cmd.Run(); err != nil {
+		log.Println("supervisor: stop command encountered an error - ignoring:", err)
+	}
+}
@@ -0,0 +1,115 @@
+package supervisor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelayExponentialWithCap(t *testing.T) {
+	s := &Supervisor{cfg: Config{BackoffBaseSeconds: 1, BackoffMaxSeconds: 10}}
+
+	cases := []struct {
+		attempt  int
+		wantBase float64 // pre-jitter delay in seconds
+	}{
+		{attempt: 1, wantBase: 1},
+		{attempt: 2, wantBase: 2},
+		{attempt: 3, wantBase: 4},
+		{attempt: 4, wantBase: 8},
+		{attempt: 5, wantBase: 10}, // would be 16, capped at BackoffMaxSeconds
+		{attempt: 10, wantBase: 10},
+	}
+
+	for _, c := range cases {
+		delay := s.backoffDelay(c.attempt)
+		min := c.wantBase
+		max := c.wantBase * 1.5
+		if got := delay.Seconds(); got < min || got > max {
+			t.Errorf("backoffDelay(%d) = %s, want between %.1fs and %.1fs", c.attempt, delay, min, max)
+		}
+	}
+}
+
+func TestBackoffDelayNeverExceedsMax(t *testing.T) {
+	s := &Supervisor{cfg: Config{BackoffBaseSeconds: 5, BackoffMaxSeconds: 60}}
+
+	for attempt := 1; attempt <= 20; attempt++ {
+		if delay := s.backoffDelay(attempt); delay.Seconds() > 90 { // max + 50% jitter
+			t.Fatalf("backoffDelay(%d) = %s, want at most 90s", attempt, delay)
+		}
+	}
+}
+
+func TestRestartDuringBackoffSkipsDelay(t *testing.T) {
+	s := New(Config{
+		StartCommand:       "false", // always exits non-zero immediately
+		StopCommand:        "true",
+		StartSeconds:       1,
+		StartRetries:       5,
+		BackoffBaseSeconds: 60,
+		BackoffMaxSeconds:  60,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	if !waitForState(t, s, StateBackoff, time.Second) {
+		t.Fatal("supervisor never reached StateBackoff")
+	}
+
+	if ok := s.Restart(); !ok {
+		t.Fatal("Restart() = false while in StateBackoff, want true")
+	}
+
+	// With a 60s backoff base, a second attempt this soon can only happen if
+	// Restart bypassed the delay rather than waiting it out.
+	deadline := time.Now().Add(time.Second)
+	for {
+		if s.Status().Retries >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Restart did not skip the backoff delay")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRestartAfterFatalReturnsFalse(t *testing.T) {
+	s := New(Config{
+		StartCommand:       "false",
+		StopCommand:        "true",
+		StartSeconds:       1,
+		StartRetries:       0,
+		BackoffBaseSeconds: 0,
+		BackoffMaxSeconds:  0,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	if !waitForState(t, s, StateFatal, time.Second) {
+		t.Fatal("supervisor never reached StateFatal")
+	}
+
+	if ok := s.Restart(); ok {
+		t.Fatal("Restart() = true while in StateFatal, want false")
+	}
+}
+
+func waitForState(t *testing.T, s *Supervisor, want State, timeout time.Duration) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		if s.Status().State == want {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}